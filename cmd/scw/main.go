@@ -0,0 +1,35 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+// Command scw is the Scaleway CLI entrypoint. It dispatches to the subcommands
+// registered in pkg/commands.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "scw: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: scw COMMAND [arg...]")
+	}
+
+	name, rest := args[0], args[1:]
+	for _, cmd := range commands.Commands {
+		if cmd.Name == name {
+			return cmd.Exec(rest)
+		}
+	}
+	return fmt.Errorf("unknown command %q", name)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+func TestRunDispatchesToRegisteredCommand(t *testing.T) {
+	called := false
+	commands.Commands = append(commands.Commands, &commands.Command{
+		Name: "test-dispatch",
+		Exec: func(args []string) error {
+			called = true
+			if len(args) != 1 || args[0] != "arg" {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return nil
+		},
+	})
+
+	if err := run([]string{"test-dispatch", "arg"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected the registered command's Exec to run")
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	err := run([]string{"does-not-exist"})
+	if err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Fatalf("expected an unknown command error, got %v", err)
+	}
+}
+
+func TestRunNoArgs(t *testing.T) {
+	if err := run(nil); err == nil {
+		t.Fatal("expected a usage error when no command is given")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+// Package bootstrap pushes a local directory to a server and runs a command against it,
+// streaming output back to the local terminal. It is the engine behind `scw run
+// --bootstrap`, inspired by the tiny untar-and-stream-output server Go's buildlet uses to
+// drive one-shot builds on ephemeral machines.
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+// RemoteDir is where the uploaded tarball is extracted on the server
+const RemoteDir = "/tmp/scw-bootstrap"
+
+// Options describes a single bootstrap run
+type Options struct {
+	// Dir is the local directory or gzip'd tarball uploaded and extracted into RemoteDir
+	Dir string
+
+	// Command is executed in RemoteDir (or Workdir, when set) once the upload completes
+	Command []string
+
+	// Env holds "KEY=VALUE" pairs exported before Command runs
+	Env []string
+
+	// Workdir overrides RemoteDir as Command's working directory
+	Workdir string
+
+	// PreScript, when set, runs in Workdir (RemoteDir when Workdir is unset) right after
+	// extraction and before Command
+	PreScript string
+
+	// PostScript, when set, runs in Workdir (RemoteDir when Workdir is unset) right after
+	// Command, whether it succeeded or not
+	PostScript string
+
+	// HaltOnExit powers the server off once Command (and PostScript) have returned
+	HaltOnExit bool
+}
+
+// Run uploads Options.Dir to the server behind client, extracts it, runs the pre-script,
+// Command and post-script in turn, and returns Command's exit code. Output is streamed to
+// the local stdout/stderr as it is produced; HaltOnExit is honored even if Command fails.
+func Run(client *utils.SSHClient, options Options) (int, error) {
+	archive, isTemp, err := prepareArchive(options.Dir)
+	if err != nil {
+		return 1, fmt.Errorf("unable to pack %s: %v", options.Dir, err)
+	}
+	if isTemp {
+		defer os.Remove(archive)
+	}
+
+	remoteArchive := path.Join("/tmp", path.Base(archive))
+	log.Debugf("bootstrap: uploading %s to %s", options.Dir, remoteArchive)
+	if err := utils.SFTPCopy(client, archive, remoteArchive, false, nil); err != nil {
+		return 1, fmt.Errorf("unable to upload %s: %v", options.Dir, err)
+	}
+
+	extract := []string{"mkdir", "-p", RemoteDir, "&&", "tar", "-xzf", remoteArchive, "-C", RemoteDir, "&&", "rm", "-f", remoteArchive}
+	if err := client.Run(extract); err != nil {
+		return 1, fmt.Errorf("unable to extract bootstrap archive on the server: %v", err)
+	}
+
+	if options.PreScript != "" {
+		if err := client.Run(options.remoteCommand(options.PreScript)); err != nil {
+			return 1, fmt.Errorf("pre-script failed: %v", err)
+		}
+	}
+
+	runErr := client.Run(options.remoteCommand(strings.Join(options.Command, " ")))
+	exitCode := utils.SSHExitCode(runErr)
+
+	if options.PostScript != "" {
+		if err := client.Run(options.remoteCommand(options.PostScript)); err != nil {
+			log.Warnf("post-script failed: %v", err)
+		}
+	}
+
+	if options.HaltOnExit {
+		log.Debugf("bootstrap: halting server as requested")
+		if err := client.Run([]string{"poweroff"}); err != nil {
+			log.Warnf("unable to halt the server: %v", err)
+		}
+	}
+
+	return exitCode, runErr
+}
+
+// remoteCommand wraps script with the cd/env preamble shared by the pre-script, Command
+// and post-script steps
+func (options Options) remoteCommand(script string) []string {
+	workdir := options.Workdir
+	if workdir == "" {
+		workdir = RemoteDir
+	}
+
+	parts := []string{"cd", workdir, "&&"}
+	if len(options.Env) > 0 {
+		parts = append(parts, "export")
+		parts = append(parts, options.Env...)
+		parts = append(parts, "&&")
+	}
+	parts = append(parts, script)
+	return parts
+}
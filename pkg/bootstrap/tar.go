@@ -0,0 +1,88 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package bootstrap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// prepareArchive resolves the `--bootstrap <dir-or-tar>` argument to a local gzip'd tar
+// ready to upload. When path is a directory it is packed with tarGzDir into a temporary
+// file (isTemp is true, so the caller should remove it once uploaded); when path is a
+// regular file it is assumed to already be a tarball and is used as-is (isTemp is false).
+func prepareArchive(path string) (archive string, isTemp bool, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false, err
+	}
+	if !info.IsDir() {
+		return path, false, nil
+	}
+	archive, err = tarGzDir(path)
+	return archive, true, err
+}
+
+// tarGzDir writes a gzip'd tar of dir to a temporary file and returns its path; the
+// caller is responsible for removing it once it has been uploaded.
+func tarGzDir(dir string) (string, error) {
+	archive, err := ioutil.TempFile("", "scw-bootstrap")
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	gzWriter := gzip.NewWriter(archive)
+	defer gzWriter.Close()
+
+	tarWriter := tar.NewWriter(gzWriter)
+	defer tarWriter.Close()
+
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tarWriter, f)
+		return err
+	})
+	if err != nil {
+		os.Remove(archive.Name())
+		return "", err
+	}
+
+	return archive.Name(), nil
+}
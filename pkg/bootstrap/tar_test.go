@@ -0,0 +1,88 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package bootstrap
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrepareArchiveDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scw-bootstrap-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "payload.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, isTemp, err := prepareArchive(dir)
+	if err != nil {
+		t.Fatalf("prepareArchive(dir) returned an error: %v", err)
+	}
+	defer os.Remove(archive)
+
+	if !isTemp {
+		t.Fatal("prepareArchive(dir) should report the archive as temporary")
+	}
+
+	names := readTarNames(t, archive)
+	if len(names) != 1 || names[0] != "payload.txt" {
+		t.Fatalf("expected the archive to contain payload.txt, got %v", names)
+	}
+}
+
+func TestPrepareArchiveFile(t *testing.T) {
+	tarball, err := ioutil.TempFile("", "scw-bootstrap-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarball.Close()
+	defer os.Remove(tarball.Name())
+
+	archive, isTemp, err := prepareArchive(tarball.Name())
+	if err != nil {
+		t.Fatalf("prepareArchive(file) returned an error: %v", err)
+	}
+	if isTemp {
+		t.Fatal("prepareArchive(file) should use the given tarball as-is")
+	}
+	if archive != tarball.Name() {
+		t.Fatalf("expected archive %q, got %q", tarball.Name(), archive)
+	}
+}
+
+func readTarNames(t *testing.T, archive string) []string {
+	t.Helper()
+
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gzReader, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gzReader.Close()
+
+	var names []string
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	return names
+}
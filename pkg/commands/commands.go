@@ -0,0 +1,30 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+// Package commands wires the SSH/SFTP/bootstrap building blocks in pkg/utils and
+// pkg/bootstrap into the scw subcommands that drive them (exec, cp, port-forward, run).
+package commands
+
+// Command describes a single scw subcommand
+type Command struct {
+	// Name is the subcommand's name, e.g. "cp" for `scw cp`
+	Name string
+
+	// UsageLine is the subcommand's one-line usage synopsis
+	UsageLine string
+
+	// Description is a one-line summary of what the subcommand does
+	Description string
+
+	// Exec runs the command against args, the CLI arguments following the subcommand name
+	Exec func(args []string) error
+}
+
+// Commands lists every registered scw subcommand, populated by each command's init()
+var Commands []*Command
+
+// register adds cmd to Commands; called from each command's init()
+func register(cmd *Command) {
+	Commands = append(Commands, cmd)
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+func init() {
+	register(&Command{
+		Name:        "cp",
+		UsageLine:   "cp [-r] [--user USER] [-i IDENTITY_FILE] SRC DST",
+		Description: "Copy files to or from a server over SFTP",
+		Exec:        cmdCp,
+	})
+}
+
+// cmdCp implements `scw cp`: exactly one of SRC/DST must be of the form [user@]HOST:PATH,
+// the other a local path. It prefers SFTPCopy and only falls back to the legacy
+// TAR-over-SSH pipe (utils.TARPipeCopy) when the server has no sftp-server subsystem.
+func cmdCp(args []string) error {
+	fs := flag.NewFlagSet("cp", flag.ContinueOnError)
+	recursive := fs.Bool("r", false, "copy directories recursively")
+	override := sshFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		return fmt.Errorf("usage: scw cp [-r] [--user USER] [-i IDENTITY_FILE] SRC DST")
+	}
+
+	remote, local, uploading, err := splitCpArgs(rest[0], rest[1])
+	if err != nil {
+		return err
+	}
+
+	client, host, options, err := dialTarget(remote.host, override)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var copyErr error
+	if uploading {
+		copyErr = utils.SFTPCopy(client, local, remote.path, *recursive, nil)
+	} else {
+		copyErr = utils.SFTPCopy(client, remote.path, local, *recursive, nil)
+	}
+	if copyErr == nil {
+		return nil
+	}
+	if !strings.HasPrefix(copyErr.Error(), "unable to start SFTP subsystem") {
+		return copyErr
+	}
+
+	log.Debugf("sftp copy failed (%v), falling back to the TAR-over-SSH pipe", copyErr)
+	return utils.TARPipeCopy(host, "", options.User, local, remote.path, uploading)
+}
+
+// remoteArg is the SERVER:PATH side of a cp invocation
+type remoteArg struct {
+	host string
+	path string
+}
+
+func splitCpArgs(src, dst string) (remote remoteArg, local string, uploading bool, err error) {
+	if r, ok := parseRemoteArg(dst); ok {
+		return r, src, true, nil
+	}
+	if r, ok := parseRemoteArg(src); ok {
+		return r, dst, false, nil
+	}
+	return remoteArg{}, "", false, fmt.Errorf("one of SRC or DST must be of the form [user@]HOST:PATH")
+}
+
+func parseRemoteArg(arg string) (remoteArg, bool) {
+	colon := strings.Index(arg, ":")
+	if colon < 0 {
+		return remoteArg{}, false
+	}
+	return remoteArg{host: arg[:colon], path: arg[colon+1:]}, true
+}
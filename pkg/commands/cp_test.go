@@ -0,0 +1,49 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import "testing"
+
+func TestSplitCpArgsUpload(t *testing.T) {
+	remote, local, uploading, err := splitCpArgs("./local", "myserver:/remote/path")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !uploading {
+		t.Fatal("expected an upload (local -> remote)")
+	}
+	if local != "./local" || remote.host != "myserver" || remote.path != "/remote/path" {
+		t.Fatalf("unexpected split: local=%q host=%q path=%q", local, remote.host, remote.path)
+	}
+}
+
+func TestSplitCpArgsDownload(t *testing.T) {
+	remote, local, uploading, err := splitCpArgs("root@myserver:/remote/path", "./local")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uploading {
+		t.Fatal("expected a download (remote -> local)")
+	}
+	if local != "./local" || remote.host != "root@myserver" || remote.path != "/remote/path" {
+		t.Fatalf("unexpected split: local=%q host=%q path=%q", local, remote.host, remote.path)
+	}
+}
+
+func TestSplitCpArgsNoRemote(t *testing.T) {
+	if _, _, _, err := splitCpArgs("./a", "./b"); err == nil {
+		t.Fatal("expected an error when neither side is SERVER:PATH")
+	}
+}
+
+func TestCpRegistered(t *testing.T) {
+	for _, cmd := range Commands {
+		if cmd.Name == "cp" {
+			return
+		}
+	}
+	t.Fatal(`expected "cp" to be registered in Commands`)
+}
+
@@ -0,0 +1,39 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+func init() {
+	register(&Command{
+		Name:        "exec",
+		UsageLine:   "exec [--user USER] [-i IDENTITY_FILE] SERVER [CMD...]",
+		Description: "Run a command on a server over SSH, or open a shell if CMD is omitted",
+		Exec:        cmdExec,
+	})
+}
+
+// cmdExec implements `scw exec SERVER [CMD...]`, opening an interactive shell on SERVER
+// when CMD is omitted, exactly like SSHExec does for every other caller in this package.
+func cmdExec(args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	override := sshFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) < 1 {
+		return fmt.Errorf("usage: scw exec [--user USER] [-i IDENTITY_FILE] SERVER [CMD...]")
+	}
+
+	host := splitHostUser(rest[0], override)
+	return utils.SSHExec(host, "", rest[1:], true, "", override)
+}
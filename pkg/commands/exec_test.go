@@ -0,0 +1,16 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import "testing"
+
+func TestExecRegistered(t *testing.T) {
+	for _, cmd := range Commands {
+		if cmd.Name == "exec" {
+			return
+		}
+	}
+	t.Fatal(`expected "exec" to be registered in Commands`)
+}
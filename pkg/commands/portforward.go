@@ -0,0 +1,66 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+func init() {
+	register(&Command{
+		Name:        "port-forward",
+		UsageLine:   "port-forward [--user USER] [-i IDENTITY_FILE] SERVER LOCAL:REMOTE",
+		Description: "Forward a local TCP port to REMOTE through SERVER's SSH connection",
+		Exec:        cmdPortForward,
+	})
+}
+
+// cmdPortForward implements `scw port-forward SERVER LOCAL:REMOTE`, the equivalent of
+// `ssh -L`, so a service reachable from SERVER (e.g. a private-IP-only database) can be
+// reached from the laptop running scw without exposing it publicly.
+func cmdPortForward(args []string) error {
+	fs := flag.NewFlagSet("port-forward", flag.ContinueOnError)
+	override := sshFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		return fmt.Errorf("usage: scw port-forward [--user USER] [-i IDENTITY_FILE] SERVER LOCAL:REMOTE")
+	}
+
+	bind, remote, err := splitForwardArg(rest[1])
+	if err != nil {
+		return err
+	}
+
+	client, _, _, err := dialTarget(rest[0], override)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return utils.SSHForwardLocal(client, bind, remote)
+}
+
+// splitForwardArg parses "LOCAL:REMOTE" the way `ssh -L` does: REMOTE is always
+// "host:port", so LOCAL is a bare port (PORT:host:port) or an explicit bind address
+// (BINDADDR:PORT:host:port).
+func splitForwardArg(arg string) (bind string, remote string, err error) {
+	parts := strings.Split(arg, ":")
+	switch len(parts) {
+	case 3:
+		return "127.0.0.1:" + parts[0], parts[1] + ":" + parts[2], nil
+	case 4:
+		return parts[0] + ":" + parts[1], parts[2] + ":" + parts[3], nil
+	default:
+		return "", "", fmt.Errorf("expected LOCAL:REMOTE (e.g. 8080:10.0.0.1:80), got %q", arg)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import "testing"
+
+func TestPortForwardRegistered(t *testing.T) {
+	for _, cmd := range Commands {
+		if cmd.Name == "port-forward" {
+			return
+		}
+	}
+	t.Fatal(`expected "port-forward" to be registered in Commands`)
+}
+
+func TestSplitForwardArgBarePort(t *testing.T) {
+	bind, remote, err := splitForwardArg("8080:10.0.0.1:80")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bind != "127.0.0.1:8080" || remote != "10.0.0.1:80" {
+		t.Fatalf("unexpected split: bind=%q remote=%q", bind, remote)
+	}
+}
+
+func TestSplitForwardArgExplicitBind(t *testing.T) {
+	bind, remote, err := splitForwardArg("0.0.0.0:8080:10.0.0.1:80")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bind != "0.0.0.0:8080" || remote != "10.0.0.1:80" {
+		t.Fatalf("unexpected split: bind=%q remote=%q", bind, remote)
+	}
+}
+
+func TestSplitForwardArgInvalid(t *testing.T) {
+	if _, _, err := splitForwardArg("notanaddress"); err == nil {
+		t.Fatal("expected an error for an arg with no colon")
+	}
+}
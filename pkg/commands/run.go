@@ -0,0 +1,99 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/bootstrap"
+)
+
+func init() {
+	register(&Command{
+		Name: "run",
+		UsageLine: "run --bootstrap DIR-OR-TAR [--workdir PATH] [--env KEY=VALUE]... " +
+			"[--pre-script SCRIPT] [--post-script SCRIPT] [--halt-on-exit] " +
+			"[--user USER] [-i IDENTITY_FILE] SERVER -- CMD...",
+		Description: "Run a command on a server after bootstrapping a local directory or tarball onto it",
+		Exec:        cmdRun,
+	})
+}
+
+// cmdRun implements `scw run --bootstrap <dir-or-tar> -- CMD...`: it pushes Dir to SERVER
+// with pkg/bootstrap, extracts it, runs CMD and streams its output back, propagating its
+// exit code.
+func cmdRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	bootstrapDir := fs.String("bootstrap", "", "local directory or gzip'd tarball to upload and extract before running CMD")
+	workdir := fs.String("workdir", "", "remote working directory for CMD (defaults to the bootstrap directory)")
+	preScript := fs.String("pre-script", "", "script run remotely right after extraction, before CMD")
+	postScript := fs.String("post-script", "", "script run remotely right after CMD")
+	haltOnExit := fs.Bool("halt-on-exit", false, "power off the server once CMD has returned")
+	var envFlags stringSliceFlag
+	fs.Var(&envFlags, "env", "KEY=VALUE pair exported before CMD runs (repeatable)")
+	override := sshFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	dashDash := indexOf(rest, "--")
+	if len(rest) < 1 || dashDash < 0 || dashDash == len(rest)-1 {
+		return fmt.Errorf("usage: scw run [flags] SERVER -- CMD...")
+	}
+	server := rest[0]
+	command := rest[dashDash+1:]
+
+	if *bootstrapDir == "" {
+		return fmt.Errorf("scw run currently requires --bootstrap; plain `scw run SERVER -- CMD` isn't wired up in this build")
+	}
+
+	client, _, _, err := dialTarget(server, override)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	exitCode, err := bootstrap.Run(client, bootstrap.Options{
+		Dir:        *bootstrapDir,
+		Command:    command,
+		Env:        envFlags,
+		Workdir:    *workdir,
+		PreScript:  *preScript,
+		PostScript: *postScript,
+		HaltOnExit: *haltOnExit,
+	})
+	if err != nil {
+		client.Close()
+		fmt.Fprintf(os.Stderr, "scw: command exited with code %d: %v\n", exitCode, err)
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// stringSliceFlag accumulates every occurrence of a repeatable flag.Var flag
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func indexOf(args []string, needle string) int {
+	for i, a := range args {
+		if a == needle {
+			return i
+		}
+	}
+	return -1
+}
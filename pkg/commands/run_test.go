@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import "testing"
+
+func TestRunRegistered(t *testing.T) {
+	for _, cmd := range Commands {
+		if cmd.Name == "run" {
+			return
+		}
+	}
+	t.Fatal(`expected "run" to be registered in Commands`)
+}
+
+func TestIndexOf(t *testing.T) {
+	if got := indexOf([]string{"a", "--", "b"}, "--"); got != 1 {
+		t.Fatalf("expected index 1, got %d", got)
+	}
+	if got := indexOf([]string{"a", "b"}, "--"); got != -1 {
+		t.Fatalf("expected -1, got %d", got)
+	}
+}
+
+func TestStringSliceFlag(t *testing.T) {
+	var s stringSliceFlag
+	if err := s.Set("A=1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("B=2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 || s[0] != "A=1" || s[1] != "B=2" {
+		t.Fatalf("unexpected slice: %v", s)
+	}
+	if s.String() != "A=1,B=2" {
+		t.Fatalf("unexpected String(): %q", s.String())
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// sshFlags registers the --user/-i overrides shared by scw exec, scw cp,
+// scw port-forward and scw run, returning the utils.SSHOptions fs.Parse fills in.
+func sshFlags(fs *flag.FlagSet) *utils.SSHOptions {
+	override := &utils.SSHOptions{}
+	fs.StringVar(&override.User, "user", "", "remote SSH user (overrides ~/.scwrc)")
+	fs.StringVar(&override.IdentityFile, "i", "", "SSH identity file (overrides ~/.scwrc)")
+	return override
+}
+
+// splitHostUser extracts an optional "user@" prefix from a [user@]HOST string, filling
+// override.User from it when override.User isn't already set (e.g. by --user), and
+// returns the bare host.
+func splitHostUser(host string, override *utils.SSHOptions) string {
+	at := strings.Index(host, "@")
+	if at < 0 {
+		return host
+	}
+	user, bareHost := host[:at], host[at+1:]
+	if override.User == "" {
+		override.User = user
+	}
+	return bareHost
+}
+
+// dialTarget opens an SSHClient to host, which may be prefixed with "user@" to override
+// the user read from ~/.scwrc; override further overrides both the user and the identity
+// file, e.g. from --user/-i flags. Server-name-to-IP resolution through the Scaleway API
+// is handled by the rest of the CLI and isn't reproduced here: host is dialed as-is, so it
+// must be an address the SSH client can reach directly (public IP or resolvable name).
+// It returns the bare host (with any "user@" prefix stripped) and the options the
+// connection was actually opened with, so callers can reuse them for a fallback path.
+func dialTarget(host string, override *utils.SSHOptions) (*utils.SSHClient, string, utils.SSHOptions, error) {
+	host = splitHostUser(host, override)
+
+	options, _ := utils.ReadSSHOptionsFromConfig()
+	if override.User != "" {
+		options.User = override.User
+	}
+	if override.IdentityFile != "" {
+		options.IdentityFile = override.IdentityFile
+	}
+
+	client, err := utils.NewSSHClient(host, "", "", options)
+	if err != nil {
+		return nil, "", utils.SSHOptions{}, fmt.Errorf("unable to connect to %s: %v", host, err)
+	}
+	return client, host, options, nil
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+func TestSplitHostUserNoPrefix(t *testing.T) {
+	override := &utils.SSHOptions{}
+	if host := splitHostUser("myserver", override); host != "myserver" || override.User != "" {
+		t.Fatalf("unexpected split: host=%q user=%q", host, override.User)
+	}
+}
+
+func TestSplitHostUserPrefix(t *testing.T) {
+	override := &utils.SSHOptions{}
+	if host := splitHostUser("root@myserver", override); host != "myserver" || override.User != "root" {
+		t.Fatalf("unexpected split: host=%q user=%q", host, override.User)
+	}
+}
+
+func TestSplitHostUserFlagWins(t *testing.T) {
+	override := &utils.SSHOptions{User: "ubuntu"}
+	if host := splitHostUser("root@myserver", override); host != "myserver" || override.User != "ubuntu" {
+		t.Fatalf("expected the --user override to win over the user@ prefix, got host=%q user=%q", host, override.User)
+	}
+}
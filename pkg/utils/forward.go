@@ -0,0 +1,86 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+// SSHForwardLocal accepts connections on bind (local host:port) and pipes each one over
+// client to remote (host:port on the server's side), equivalent to `ssh -L bind:remote`.
+// It blocks serving connections until the listener is closed or accepting fails.
+func SSHForwardLocal(client *SSHClient, bind string, remote string) error {
+	listener, err := net.Listen("tcp", bind)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %v", bind, err)
+	}
+	defer listener.Close()
+
+	log.Debugf("Forwarding %s to %s", bind, remote)
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer local.Close()
+			upstream, err := client.Dial("tcp", remote)
+			if err != nil {
+				log.Warnf("port-forward: unable to reach %s: %v", remote, err)
+				return
+			}
+			defer upstream.Close()
+			pipeConns(local, upstream)
+		}()
+	}
+}
+
+// SSHForwardRemote asks the server to listen on remote (host:port on the server's side)
+// and pipes every accepted connection back to bind (local host:port), equivalent to
+// `ssh -R remote:bind`. It blocks serving connections until the server-side listener is
+// closed or accepting fails.
+func SSHForwardRemote(client *SSHClient, remote string, bind string) error {
+	listener, err := client.client.Listen("tcp", remote)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s through the server: %v", remote, err)
+	}
+	defer listener.Close()
+
+	log.Debugf("Forwarding %s to %s", remote, bind)
+	for {
+		upstream, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer upstream.Close()
+			local, err := net.Dial("tcp", bind)
+			if err != nil {
+				log.Warnf("port-forward: unable to reach %s: %v", bind, err)
+				return
+			}
+			defer local.Close()
+			pipeConns(upstream, local)
+		}()
+	}
+}
+
+// pipeConns copies data in both directions between a and b until one side closes
+func pipeConns(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPipeConns(t *testing.T) {
+	aLocal, aRemote := net.Pipe()
+	bLocal, bRemote := net.Pipe()
+
+	go pipeConns(aRemote, bRemote)
+
+	go func() {
+		aLocal.Write([]byte("ping"))
+		aLocal.Close()
+	}()
+
+	buf := make([]byte, 4)
+	bLocal.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := io.ReadFull(bLocal, buf)
+	if err != nil {
+		t.Fatalf("expected to read 'ping' forwarded through pipeConns, got err: %v", err)
+	}
+	if string(buf[:n]) != "ping" {
+		t.Fatalf("expected 'ping', got %q", buf[:n])
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/scaleway/scaleway-cli/vendor/github.com/gorilla/websocket"
+	"github.com/scaleway/scaleway-cli/vendor/golang.org/x/term"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+// detachKey is Ctrl-] (0x1d), the traditional telnet/serial escape character
+const detachKey = 0x1d
+
+// containsDetachKey reports whether buf contains the detach keystroke
+func containsDetachKey(buf []byte) bool {
+	for _, b := range buf {
+		if b == detachKey {
+			return true
+		}
+	}
+	return false
+}
+
+// SerialClient connects to a server's serial console over the Scaleway websocket gateway
+// and pipes the local terminal to it, replacing the Node.js 'termjs-cli' dependency.
+type SerialClient struct {
+	serverID    string
+	apiToken    string
+	attachStdin bool
+}
+
+// NewSerialClient builds a SerialClient ready to Attach() to serverID's console
+func NewSerialClient(serverID string, apiToken string, attachStdin bool) *SerialClient {
+	return &SerialClient{
+		serverID:    serverID,
+		apiToken:    apiToken,
+		attachStdin: attachStdin,
+	}
+}
+
+// Attach dials the serial console websocket, switches the local terminal to raw mode and
+// bidirectionally pipes stdin/stdout until the connection closes or the user detaches
+// with Ctrl-].
+func (s *SerialClient) Attach() error {
+	url := fmt.Sprintf("wss://tty.cloud.online.net/?server_id=%s&type=serial&auth_token=%s", s.serverID, s.apiToken)
+
+	log.Debugf("Dialing serial console: %s", url)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to reach the serial console: %v", err)
+	}
+	defer conn.Close()
+
+	if s.attachStdin {
+		fd := int(os.Stdin.Fd())
+		if term.IsTerminal(fd) {
+			oldState, err := term.MakeRaw(fd)
+			if err != nil {
+				return fmt.Errorf("unable to set terminal to raw mode: %v", err)
+			}
+			defer term.Restore(fd, oldState)
+		}
+	}
+
+	s.handleResize(conn)
+
+	done := make(chan error, 2)
+	go s.pipeToStdout(conn, done)
+	if s.attachStdin {
+		go s.pipeFromStdin(conn, done)
+	}
+	return <-done
+}
+
+// pipeToStdout forwards every text/binary frame received from the console to stdout
+func (s *SerialClient) pipeToStdout(conn *websocket.Conn, done chan<- error) {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			done <- err
+			return
+		}
+		if _, err := os.Stdout.Write(message); err != nil {
+			done <- err
+			return
+		}
+	}
+}
+
+// pipeFromStdin reads local keystrokes and forwards them to the console, detaching
+// cleanly on Ctrl-]
+func (s *SerialClient) pipeFromStdin(conn *websocket.Conn, done chan<- error) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				done <- nil
+				return
+			}
+			done <- err
+			return
+		}
+		if containsDetachKey(buf[:n]) {
+			done <- nil
+			return
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
+			done <- err
+			return
+		}
+	}
+}
+
+// handleResize sends the terminal size to the console whenever it changes, so remote
+// programs keep the right COLUMNS/LINES; it is a best-effort, non-blocking watcher.
+func (s *SerialClient) handleResize(conn *websocket.Conn) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+
+	sendSize := func() {
+		width, height, err := term.GetSize(int(os.Stdin.Fd()))
+		if err != nil {
+			return
+		}
+		resize := fmt.Sprintf(`{"cols":%d,"rows":%d}`, width, height)
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(resize)); err != nil {
+			log.Debugf("serial: unable to send window size: %v", err)
+		}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	notifyWinch(sigCh)
+	go func() {
+		sendSize()
+		for range sigCh {
+			sendSize()
+		}
+	}()
+}
+
+// notifyWinch subscribes ch to the platform's terminal-resize signal, if any
+func notifyWinch(ch chan<- os.Signal) {
+	signals := windowChangeSignal()
+	if len(signals) == 0 {
+		return
+	}
+	signal.Notify(ch, signals...)
+}
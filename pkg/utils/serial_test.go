@@ -0,0 +1,16 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import "testing"
+
+func TestContainsDetachKey(t *testing.T) {
+	if containsDetachKey([]byte("hello")) {
+		t.Error("expected no detach key in plain input")
+	}
+	if !containsDetachKey([]byte{'a', detachKey, 'b'}) {
+		t.Error("expected detach key to be found")
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+// +build !windows
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+// windowChangeSignal returns the OS signal sent when the terminal is resized
+func windowChangeSignal() []os.Signal {
+	return []os.Signal{syscall.SIGWINCH}
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+// +build windows
+
+package utils
+
+import "os"
+
+// windowChangeSignal returns the OS signal sent when the terminal is resized; Windows
+// has no SIGWINCH equivalent, so there is nothing to subscribe to here.
+func windowChangeSignal() []os.Signal {
+	return nil
+}
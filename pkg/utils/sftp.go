@@ -0,0 +1,140 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/scaleway/scaleway-cli/vendor/github.com/pkg/sftp"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+// SFTPCopy copies src to dst over client's SFTP subsystem, recursing into directories
+// when recursive is true and preserving file modes/mtimes along the way. Direction is
+// inferred like scp: if src exists on the local filesystem it is uploaded to the remote
+// dst, otherwise src is taken to be a remote path and downloaded to the local dst.
+//
+// progress, when non-nil, receives a copy of every byte transferred so callers can report
+// upload/download progress (e.g. wrap it in a progress-bar writer).
+func SFTPCopy(client *SSHClient, src, dst string, recursive bool, progress io.Writer) error {
+	sftpClient, err := sftp.NewClient(client.client)
+	if err != nil {
+		return fmt.Errorf("unable to start SFTP subsystem: %v", err)
+	}
+	defer sftpClient.Close()
+
+	if _, err := os.Stat(src); err == nil {
+		return sftpUpload(sftpClient, src, dst, recursive, progress)
+	}
+	return sftpDownload(sftpClient, src, dst, recursive, progress)
+}
+
+func sftpUpload(client *sftp.Client, localPath, remotePath string, recursive bool, progress io.Writer) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if !recursive {
+			return fmt.Errorf("%s is a directory, use recursive copy", localPath)
+		}
+		if err := client.MkdirAll(remotePath); err != nil {
+			return err
+		}
+		entries, err := ioutil.ReadDir(localPath)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := sftpUpload(client, filepath.Join(localPath, entry.Name()), path.Join(remotePath, entry.Name()), recursive, progress); err != nil {
+				return err
+			}
+		}
+		return client.Chtimes(remotePath, info.ModTime(), info.ModTime())
+	}
+
+	log.Debugf("SFTP: uploading %s to %s", localPath, remotePath)
+	srcFile, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := client.Create(remotePath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	if err := copyWithProgress(dstFile, srcFile, progress); err != nil {
+		return err
+	}
+	if err := client.Chmod(remotePath, info.Mode()); err != nil {
+		return err
+	}
+	return client.Chtimes(remotePath, info.ModTime(), info.ModTime())
+}
+
+func sftpDownload(client *sftp.Client, remotePath, localPath string, recursive bool, progress io.Writer) error {
+	info, err := client.Stat(remotePath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if !recursive {
+			return fmt.Errorf("%s is a directory, use recursive copy", remotePath)
+		}
+		if err := os.MkdirAll(localPath, info.Mode()); err != nil {
+			return err
+		}
+		entries, err := client.ReadDir(remotePath)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := sftpDownload(client, path.Join(remotePath, entry.Name()), filepath.Join(localPath, entry.Name()), recursive, progress); err != nil {
+				return err
+			}
+		}
+		return os.Chtimes(localPath, info.ModTime(), info.ModTime())
+	}
+
+	log.Debugf("SFTP: downloading %s to %s", remotePath, localPath)
+	srcFile, err := client.Open(remotePath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	if err := copyWithProgress(dstFile, srcFile, progress); err != nil {
+		return err
+	}
+	if err := os.Chmod(localPath, info.Mode()); err != nil {
+		return err
+	}
+	return os.Chtimes(localPath, info.ModTime(), info.ModTime())
+}
+
+func copyWithProgress(dst io.Writer, src io.Reader, progress io.Writer) error {
+	if progress != nil {
+		dst = io.MultiWriter(dst, progress)
+	}
+	_, err := io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/vendor/golang.org/x/crypto/ssh"
+	"github.com/scaleway/scaleway-cli/vendor/golang.org/x/crypto/ssh/agent"
+
+	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
+)
+
+// DefaultSSHConnectTimeout is the default delay we wait for a TCP handshake on port 22
+const DefaultSSHConnectTimeout = 5 * time.Second
+
+// SSHOptions groups the parameters used to open a connection with SSHClient
+type SSHOptions struct {
+	// User is the remote user used to authenticate, defaults to "root"
+	User string
+
+	// IdentityFile overrides the default ~/.ssh/id_rsa private key
+	IdentityFile string
+
+	// ConnectTimeout bounds how long dialing the server/gateway may take
+	ConnectTimeout time.Duration
+}
+
+// SSHClient wraps a connection opened with the native SSH implementation, optionally
+// tunneled through a gateway, and exposes helpers to run commands or open a shell.
+type SSHClient struct {
+	client  *ssh.Client
+	gateway *ssh.Client // set when client was dialed through a gateway; closed alongside client
+	options SSHOptions
+}
+
+// NewSSHClient dials publicIPAddress (or privateIPAddress through gatewayIPAddress when
+// set) and authenticates using the identity file and/or ssh-agent described by options.
+func NewSSHClient(publicIPAddress string, privateIPAddress string, gatewayIPAddress string, options SSHOptions) (*SSHClient, error) {
+	if publicIPAddress == "" && gatewayIPAddress == "" {
+		return nil, errors.New("server does not have public IP")
+	}
+	if privateIPAddress == "" && gatewayIPAddress != "" {
+		return nil, errors.New("server does not have private IP")
+	}
+
+	config, err := newSSHClientConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var sshClient, gatewayClient *ssh.Client
+	if gatewayIPAddress == "" {
+		sshClient, err = dialWithTimeout("tcp", net.JoinHostPort(publicIPAddress, "22"), config)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to %s: %v", publicIPAddress, err)
+		}
+	} else {
+		gatewayClient, err = dialWithTimeout("tcp", net.JoinHostPort(gatewayIPAddress, "22"), config)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to gateway %s: %v", gatewayIPAddress, err)
+		}
+
+		target := net.JoinHostPort(privateIPAddress, "22")
+		conn, err := gatewayClient.Dial("tcp", target)
+		if err != nil {
+			gatewayClient.Close()
+			return nil, fmt.Errorf("unable to reach %s through gateway %s: %v", privateIPAddress, gatewayIPAddress, err)
+		}
+		ncc, chans, reqs, err := ssh.NewClientConn(conn, target, config)
+		if err != nil {
+			gatewayClient.Close()
+			return nil, fmt.Errorf("unable to authenticate with %s through gateway %s: %v", privateIPAddress, gatewayIPAddress, err)
+		}
+		sshClient = ssh.NewClient(ncc, chans, reqs)
+	}
+
+	client := &SSHClient{client: sshClient, gateway: gatewayClient, options: options}
+	if !isRoot(options.User) {
+		if err := client.probeSudo(); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// isRoot reports whether user designates the root account (the implicit default)
+func isRoot(user string) bool {
+	return user == "" || user == "root"
+}
+
+// probeSudo runs 'whoami' and 'sudo -n true' on a fresh connection so non-root callers
+// fail fast with a clear error instead of discovering mid-command that passwordless sudo
+// isn't configured on the image.
+func (c *SSHClient) probeSudo() error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to open SSH session: %v", err)
+	}
+	defer session.Close()
+
+	if out, err := session.CombinedOutput("whoami && sudo -n true"); err != nil {
+		return fmt.Errorf("user %q does not have passwordless sudo configured on this server: %v\n%s", c.options.User, err, out)
+	}
+	return nil
+}
+
+// dialWithTimeout is ssh.Dial with a bounded TCP handshake instead of the default
+// (potentially unbounded) dialer, so callers get a clear timeout error instead of hanging.
+func dialWithTimeout(network, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultSSHConnectTimeout
+	}
+	conn, err := net.DialTimeout(network, addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// newSSHClientConfig builds the ssh.ClientConfig, gathering auth methods from the
+// identity file (or ~/.ssh/id_rsa by default) and a running ssh-agent.
+func newSSHClientConfig(options SSHOptions) (*ssh.ClientConfig, error) {
+	user := options.User
+	if user == "" {
+		user = "root"
+	}
+
+	var authMethods []ssh.AuthMethod
+
+	identityFile := options.IdentityFile
+	if identityFile == "" {
+		homeDir, err := GetHomeDir()
+		if err == nil {
+			identityFile = filepath.Join(homeDir, ".ssh", "id_rsa")
+		}
+	}
+	if identityFile != "" {
+		if signer, err := loadPrivateKey(identityFile); err == nil {
+			authMethods = append(authMethods, ssh.PublicKeys(signer))
+		} else {
+			log.Debugf("SSH: not using identity file %s: %v", identityFile, err)
+		}
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			authMethods = append(authMethods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		} else {
+			log.Debugf("SSH: unable to reach ssh-agent at %s: %v", sock, err)
+		}
+	}
+
+	if len(authMethods) == 0 {
+		return nil, errors.New("no SSH authentication method available (no identity file, no ssh-agent)")
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            authMethods,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         options.ConnectTimeout,
+	}, nil
+}
+
+func loadPrivateKey(path string) (ssh.Signer, error) {
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(key)
+}
+
+// Session opens a new SSH session on the underlying connection, ready to be customized
+// by callers that need more control than Run/Shell provide (e.g. SFTP, port-forwarding).
+func (c *SSHClient) Session() (*ssh.Session, error) {
+	return c.client.NewSession()
+}
+
+// Dial opens a connection from the remote side of the SSH session, used to implement
+// local tunnels through the server/gateway (see SSHForwardLocal).
+func (c *SSHClient) Dial(network, addr string) (net.Conn, error) {
+	return c.client.Dial(network, addr)
+}
+
+// Close terminates the underlying SSH connection, along with the gateway connection it
+// was tunneled through, if any
+func (c *SSHClient) Close() error {
+	err := c.client.Close()
+	if c.gateway != nil {
+		if gwErr := c.gateway.Close(); gwErr != nil && err == nil {
+			err = gwErr
+		}
+	}
+	return err
+}
+
+// Run executes command on the remote server, plumbing stdio like the exec'd ssh binary did.
+// When options.User is a non-root user, the command is wrapped in a passwordless sudo call.
+func (c *SSHClient) Run(command []string) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to open SSH session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	remoteCmd := remoteShellCommand(command, c.options.User)
+	log.Debugf("Executing over SSH: %s", remoteCmd)
+	return session.Run(remoteCmd)
+}
+
+// remoteShellCommand builds the shell command line run on the server, escalating through
+// 'sudo -n' when user isn't root.
+func remoteShellCommand(command []string, user string) string {
+	shellCmd := fmt.Sprintf("/bin/sh -e -c %s", fmt.Sprintf("%q", strings.Join(command, " ")))
+	if isRoot(user) {
+		return shellCmd
+	}
+	return fmt.Sprintf("sudo -n %s", shellCmd)
+}
+
+// exitStatuser is implemented by golang.org/x/crypto/ssh's *ExitError; asserting against
+// this interface instead of the concrete type keeps SSHExitCode testable without a real
+// ssh.ExitError, which can only be constructed by the ssh package itself.
+type exitStatuser interface {
+	ExitStatus() int
+}
+
+// SSHExitCode extracts the remote command's exit status from an error returned by
+// SSHClient.Run/Shell, defaulting to 1 when the error doesn't carry one (e.g. the
+// connection dropped before the remote process could exit).
+func SSHExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(exitStatuser); ok {
+		return exitErr.ExitStatus()
+	}
+	return 1
+}
+
+// Shell opens an interactive login shell on the remote server, allocating a pty when tty is true
+func (c *SSHClient) Shell(tty bool) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("unable to open SSH session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	if tty {
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty("xterm", 24, 80, modes); err != nil {
+			return fmt.Errorf("unable to allocate a pty: %v", err)
+		}
+	}
+
+	if err := session.Shell(); err != nil {
+		return err
+	}
+	return session.Wait()
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsRoot(t *testing.T) {
+	cases := map[string]bool{
+		"":       true,
+		"root":   true,
+		"ubuntu": false,
+		"admin":  false,
+	}
+	for user, want := range cases {
+		if got := isRoot(user); got != want {
+			t.Errorf("isRoot(%q) = %v, want %v", user, got, want)
+		}
+	}
+}
+
+func TestSSHExitCodeNilError(t *testing.T) {
+	if got := SSHExitCode(nil); got != 0 {
+		t.Errorf("SSHExitCode(nil) = %d, want 0", got)
+	}
+}
+
+// fakeExitError implements exitStatuser without needing a real ssh.ExitError, which can
+// only be constructed by the ssh package itself.
+type fakeExitError struct{ status int }
+
+func (e fakeExitError) Error() string   { return "fake exit error" }
+func (e fakeExitError) ExitStatus() int { return e.status }
+
+func TestSSHExitCodeExitError(t *testing.T) {
+	if got := SSHExitCode(fakeExitError{status: 3}); got != 3 {
+		t.Errorf("SSHExitCode(exit 3) = %d, want 3", got)
+	}
+}
+
+func TestSSHExitCodeDefaultsToOne(t *testing.T) {
+	if got := SSHExitCode(errors.New("connection reset")); got != 1 {
+		t.Errorf("SSHExitCode(plain error) = %d, want 1", got)
+	}
+}
+
+func TestRemoteShellCommandRoot(t *testing.T) {
+	got := remoteShellCommand([]string{"echo", "hi"}, "root")
+	want := `/bin/sh -e -c "echo hi"`
+	if got != want {
+		t.Errorf("remoteShellCommand(root) = %q, want %q", got, want)
+	}
+}
+
+func TestRemoteShellCommandNonRoot(t *testing.T) {
+	got := remoteShellCommand([]string{"echo", "hi"}, "ubuntu")
+	want := `sudo -n /bin/sh -e -c "echo hi"`
+	if got != want {
+		t.Errorf("remoteShellCommand(ubuntu) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// scwrcSSHFields is the subset of ~/.scwrc this package cares about; unknown fields are
+// ignored so this stays in sync with the rest of the config without knowing its shape.
+type scwrcSSHFields struct {
+	SSHUser         string `json:"ssh_user"`
+	SSHIdentityFile string `json:"ssh_identity_file"`
+}
+
+// ReadSSHOptionsFromConfig loads ssh_user/ssh_identity_file from ~/.scwrc, if present, so
+// SSHExec/SSHClient callers default to the user's configured SSH identity. Callers that
+// also expose --user/-i flags should apply those on top of the returned SSHOptions.
+func ReadSSHOptionsFromConfig() (SSHOptions, error) {
+	configPath, err := GetConfigFilePath()
+	if err != nil {
+		return SSHOptions{}, err
+	}
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return SSHOptions{}, err
+	}
+
+	var fields scwrcSSHFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return SSHOptions{}, err
+	}
+
+	return SSHOptions{
+		User:         fields.SSHUser,
+		IdentityFile: fields.SSHIdentityFile,
+	}, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSSHOptionsFromConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scwrc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	scwrc := filepath.Join(dir, ".scwrc")
+	contents := `{"organization":"unrelated-field","ssh_user":"ubuntu","ssh_identity_file":"/home/ubuntu/.ssh/id_rsa"}`
+	if err := ioutil.WriteFile(scwrc, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreHome := setEnv(t, "HOME", dir)
+	defer restoreHome()
+	restoreUserProfile := setEnv(t, "USERPROFILE", dir)
+	defer restoreUserProfile()
+
+	options, err := ReadSSHOptionsFromConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if options.User != "ubuntu" {
+		t.Errorf("User = %q, want %q", options.User, "ubuntu")
+	}
+	if options.IdentityFile != "/home/ubuntu/.ssh/id_rsa" {
+		t.Errorf("IdentityFile = %q, want %q", options.IdentityFile, "/home/ubuntu/.ssh/id_rsa")
+	}
+}
+
+// setEnv sets an environment variable for the duration of a test and returns a func that
+// restores its previous value
+func setEnv(t *testing.T, key, value string) func() {
+	t.Helper()
+	previous, had := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if had {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
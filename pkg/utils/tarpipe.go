@@ -0,0 +1,77 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// TARPipeCopy copies localPath to/from remotePath on publicIPAddress by piping tar through
+// the legacy exec'd ssh binary (NewSSHExecCmd), the fallback scw cp falls back to when
+// SFTPCopy fails because the server has no sftp-server subsystem.
+func TARPipeCopy(publicIPAddress string, gatewayIPAddress string, user string, localPath string, remotePath string, upload bool) error {
+	localDir, localBase := PathToTARPathparts(localPath)
+	remoteDir, remoteBase := PathToTARPathparts(remotePath)
+
+	if upload {
+		return tarPipeUpload(publicIPAddress, gatewayIPAddress, user, localDir, localBase, remoteDir)
+	}
+	return tarPipeDownload(publicIPAddress, gatewayIPAddress, user, remoteDir, remoteBase, localDir)
+}
+
+func tarPipeUpload(publicIPAddress, gatewayIPAddress, user, localDir, localBase, remoteDir string) error {
+	remoteCommand := []string{"mkdir", "-p", remoteDir, "&&", "tar", "xf", "-", "-C", remoteDir}
+	sshArgs := NewSSHExecCmd(publicIPAddress, "", false, nil, remoteCommand, gatewayIPAddress, user)
+
+	localTar := exec.Command("tar", "cf", "-", "-C", localDir, localBase)
+	sshCmd := exec.Command("ssh", sshArgs...)
+
+	pipe, err := localTar.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("unable to pipe tar into ssh: %v", err)
+	}
+	sshCmd.Stdin = pipe
+	sshCmd.Stdout = os.Stdout
+	sshCmd.Stderr = os.Stderr
+
+	if err := sshCmd.Start(); err != nil {
+		return fmt.Errorf("unable to start ssh: %v", err)
+	}
+	if err := localTar.Run(); err != nil {
+		return fmt.Errorf("unable to tar %s: %v", localBase, err)
+	}
+	return sshCmd.Wait()
+}
+
+func tarPipeDownload(publicIPAddress, gatewayIPAddress, user, remoteDir, remoteBase, localDir string) error {
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s: %v", localDir, err)
+	}
+
+	remoteCommand := []string{"tar", "cf", "-", "-C", remoteDir, remoteBase}
+	sshArgs := NewSSHExecCmd(publicIPAddress, "", false, nil, remoteCommand, gatewayIPAddress, user)
+
+	sshCmd := exec.Command("ssh", sshArgs...)
+	localTar := exec.Command("tar", "xf", "-", "-C", localDir)
+
+	pipe, err := sshCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("unable to pipe ssh into tar: %v", err)
+	}
+	localTar.Stdin = pipe
+	sshCmd.Stderr = os.Stderr
+	localTar.Stdout = os.Stdout
+	localTar.Stderr = os.Stderr
+
+	if err := localTar.Start(); err != nil {
+		return fmt.Errorf("unable to start tar: %v", err)
+	}
+	if err := sshCmd.Run(); err != nil {
+		return fmt.Errorf("unable to run ssh: %v", err)
+	}
+	return localTar.Wait()
+}
@@ -12,62 +12,58 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
-
-	log "github.com/scaleway/scaleway-cli/vendor/github.com/Sirupsen/logrus"
 )
 
-// quoteShellArgs transforms an array of shell arguments ([]string) into a copy/paste-able string
-func quoteShellArgs(args []string) string {
-	output := ""
-	for _, arg := range args {
-		output += " "
-		output += strconv.Quote(arg)
-	}
-	return output
-}
-
 // SSHExec executes a command over SSH and redirects file-descriptors
-func SSHExec(publicIPAddress string, privateIPAddress string, command []string, checkConnection bool, gatewayIPAddress string) error {
-	if publicIPAddress == "" && gatewayIPAddress == "" {
-		return errors.New("server does not have public IP")
-	}
-	if privateIPAddress == "" && gatewayIPAddress != "" {
-		return errors.New("server does not have private IP")
-	}
-
-	if checkConnection {
-		useGateway := gatewayIPAddress != ""
-		if useGateway && !IsTCPPortOpen(fmt.Sprintf("%s:22", gatewayIPAddress)) {
-			return errors.New("gateway is not available, try again later")
+//
+// This is a thin wrapper around SSHClient kept so existing callers don't have to change:
+// it dials with a short connect timeout instead of the old IsTCPPortOpen pre-check, and
+// runs the command (or opens a shell when command is empty) using the native SSH client.
+// The remote user (and, when it isn't root, the sudo escalation it implies) comes from
+// ~/.scwrc (see ReadSSHOptionsFromConfig). override is variadic so existing 5-argument
+// callers keep compiling unchanged; callers that need --user/-i overrides pass one.
+func SSHExec(publicIPAddress string, privateIPAddress string, command []string, checkConnection bool, gatewayIPAddress string, override ...*SSHOptions) error {
+	options, _ := ReadSSHOptionsFromConfig()
+	if len(override) > 0 && override[0] != nil {
+		if override[0].User != "" {
+			options.User = override[0].User
 		}
-		if !useGateway && !IsTCPPortOpen(fmt.Sprintf("%s:22", publicIPAddress)) {
-			return errors.New("server is not ready, try again later")
+		if override[0].IdentityFile != "" {
+			options.IdentityFile = override[0].IdentityFile
 		}
 	}
+	if checkConnection {
+		options.ConnectTimeout = DefaultSSHConnectTimeout
+	}
 
-	execCmd := append(NewSSHExecCmd(publicIPAddress, privateIPAddress, true, nil, command, gatewayIPAddress))
-
-	log.Debugf("Executing: ssh %s", quoteShellArgs(execCmd))
+	client, err := NewSSHClient(publicIPAddress, privateIPAddress, gatewayIPAddress, options)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
 
-	spawn := exec.Command("ssh", execCmd...)
-	spawn.Stdout = os.Stdout
-	spawn.Stdin = os.Stdin
-	spawn.Stderr = os.Stderr
-	return spawn.Run()
+	if len(command) == 0 {
+		return client.Shell(true)
+	}
+	return client.Run(command)
 }
 
-// NewSSHExecCmd computes execve compatible arguments to run a command via ssh
-func NewSSHExecCmd(publicIPAddress string, privateIPAddress string, allocateTTY bool, sshOptions []string, command []string, gatewayIPAddress string) []string {
+// NewSSHExecCmd computes execve compatible arguments to run a command via ssh. When user
+// is not root, the remote command is wrapped in a passwordless sudo call, mirroring
+// SSHClient.Run's escalation so the legacy exec'd-ssh path behaves the same way.
+func NewSSHExecCmd(publicIPAddress string, privateIPAddress string, allocateTTY bool, sshOptions []string, command []string, gatewayIPAddress string, user string) []string {
 	useGateway := gatewayIPAddress != ""
 	execCmd := []string{}
 
+	if user == "" {
+		user = "root"
+	}
+
 	if os.Getenv("DEBUG") != "1" {
 		execCmd = append(execCmd, "-q")
 	}
@@ -80,9 +76,9 @@ func NewSSHExecCmd(publicIPAddress string, privateIPAddress string, allocateTTY
 		execCmd = append(execCmd, strings.Join(sshOptions, " "))
 	}
 
-	execCmd = append(execCmd, "-l", "root")
+	execCmd = append(execCmd, "-l", user)
 	if useGateway {
-		proxyCommand := NewSSHExecCmd(gatewayIPAddress, "", allocateTTY, []string{"-W", "%h:%p"}, nil, "")
+		proxyCommand := NewSSHExecCmd(gatewayIPAddress, "", allocateTTY, []string{"-W", "%h:%p"}, nil, "", user)
 		execCmd = append(execCmd, privateIPAddress, "-o", "ProxyCommand=ssh "+strings.Join(proxyCommand, " "))
 	} else {
 		execCmd = append(execCmd, publicIPAddress)
@@ -93,7 +89,11 @@ func NewSSHExecCmd(publicIPAddress string, privateIPAddress string, allocateTTY
 	}
 
 	if len(command) > 0 {
-		execCmd = append(execCmd, "--", "/bin/sh", "-e")
+		if user != "root" {
+			execCmd = append(execCmd, "--", "sudo", "-n", "sh", "-e")
+		} else {
+			execCmd = append(execCmd, "--", "/bin/sh", "-e")
+		}
 
 		if os.Getenv("DEBUG") == "1" {
 			execCmd = append(execCmd, "-x")
@@ -144,6 +144,9 @@ func Wordify(str string) string {
 }
 
 // PathToTARPathparts returns the two parts of a unix path
+//
+// This backs the legacy TAR-over-SSH copy pipe, kept as a fallback for servers whose
+// sftp-server subsystem is unavailable; SFTPCopy is preferred otherwise.
 func PathToTARPathparts(fullPath string) (string, string) {
 	fullPath = strings.TrimRight(fullPath, "/")
 	return path.Dir(fullPath), path.Base(fullPath)
@@ -187,36 +190,8 @@ func GetConfigFilePath() (string, error) {
 	return filepath.Join(path, ".scwrc"), nil
 }
 
-const termjsBin string = "termjs-cli"
-
-// AttachToSerial tries to connect to server serial using 'term.js-cli' and fallback with a help message
+// AttachToSerial connects to a server's serial console over the websocket gateway and
+// pipes the local terminal to it. See serial.go for the implementation.
 func AttachToSerial(serverID string, apiToken string, attachStdin bool) error {
-	termjsURL := fmt.Sprintf("https://tty.cloud.online.net?server_id=%s&type=serial&auth_token=%s", serverID, apiToken)
-
-	args := []string{}
-	if !attachStdin {
-		args = append(args, "--no-stdin")
-	}
-	args = append(args, termjsURL)
-	log.Debugf("Executing: %s %v", termjsBin, args)
-	// FIXME: check if termjs-cli is installed
-	spawn := exec.Command(termjsBin, args...)
-	spawn.Stdout = os.Stdout
-	spawn.Stdin = os.Stdin
-	spawn.Stderr = os.Stderr
-	err := spawn.Run()
-	if err != nil {
-		log.Warnf(`
-You need to install '%s' from https://github.com/moul/term.js-cli
-
-    npm install -g term.js-cli
-
-However, you can access your serial using a web browser:
-
-    %s
-
-`, termjsBin, termjsURL)
-		return err
-	}
-	return nil
+	return NewSerialClient(serverID, apiToken, attachStdin).Attach()
 }
\ No newline at end of file